@@ -0,0 +1,234 @@
+package actionlint
+
+// exprField identifies which field of a node's parent a child occupies. It
+// is used by Cursor to know how to write a replacement back into the parent
+// without a type switch at every call site.
+type exprField int
+
+const (
+	exprFieldNone exprField = iota
+	exprFieldReceiver
+	exprFieldOperand
+	exprFieldIndex
+	exprFieldLeft
+	exprFieldRight
+	exprFieldArg
+)
+
+// Cursor describes the node currently being visited by Apply, its parent,
+// and which field of the parent it occupies. It also lets the visitor
+// rewrite the tree in place.
+type Cursor struct {
+	node   ExprNode
+	parent ExprNode
+	field  exprField
+	// index is the position in the parent FuncCallNode's Args slice. It is
+	// only meaningful when field is exprFieldArg.
+	index int
+}
+
+// Node returns the node currently being visited.
+func (c *Cursor) Node() ExprNode {
+	return c.node
+}
+
+// Parent returns the parent of the node currently being visited, or nil if
+// the current node is the root passed to Apply.
+func (c *Cursor) Parent() ExprNode {
+	return c.parent
+}
+
+// Index returns the position of the current node in its parent's Args slice,
+// when the current node is a FuncCallNode argument. Otherwise it returns -1.
+func (c *Cursor) Index() int {
+	if c.field != exprFieldArg {
+		return -1
+	}
+	return c.index
+}
+
+// Replace replaces the node currently being visited with n, re-parenting n
+// under the current parent. When called from pre, children of n are visited
+// next, exactly as if n had been there from the start; when called from post,
+// the children have already been visited and n's children are not visited.
+func (c *Cursor) Replace(n ExprNode) {
+	switch p := c.parent.(type) {
+	case nil:
+		// n is the new root; there is no parent field to rewrite.
+	case *ObjectDerefNode:
+		p.Receiver = n
+	case *ArrayDerefNode:
+		p.Receiver = n
+	case *IndexAccessNode:
+		if c.field == exprFieldIndex {
+			p.Index = n
+		} else {
+			p.Operand = n
+		}
+	case *NotOpNode:
+		p.Operand = n
+	case *CompareOpNode:
+		if c.field == exprFieldLeft {
+			p.Left = n
+		} else {
+			p.Right = n
+		}
+	case *LogicalOpNode:
+		if c.field == exprFieldLeft {
+			p.Left = n
+		} else {
+			p.Right = n
+		}
+	case *FuncCallNode:
+		p.Args[c.index] = n
+	}
+	setExprParent(n, c.parent)
+	c.node = n
+}
+
+// Delete removes the current node from its parent. It only makes sense, and
+// is only allowed, when the current node is a FuncCallNode argument: every
+// other field is a required child of its parent, so deleting it would leave
+// the tree in a state no other ExprNode consumer could handle.
+func (c *Cursor) Delete() {
+	p, ok := c.parent.(*FuncCallNode)
+	if !ok || c.field != exprFieldArg {
+		panic("actionlint.Cursor.Delete: current node is not a function call argument")
+	}
+	p.Args = append(p.Args[:c.index], p.Args[c.index+1:]...)
+}
+
+// InsertBefore inserts n as a new FuncCallNode argument immediately before
+// the current node. It panics if the current node is not a function call
+// argument.
+func (c *Cursor) InsertBefore(n ExprNode) {
+	c.insertArg(n, c.index)
+}
+
+// InsertAfter inserts n as a new FuncCallNode argument immediately after the
+// current node. It panics if the current node is not a function call
+// argument.
+func (c *Cursor) InsertAfter(n ExprNode) {
+	c.insertArg(n, c.index+1)
+}
+
+func (c *Cursor) insertArg(n ExprNode, at int) {
+	p, ok := c.parent.(*FuncCallNode)
+	if !ok || c.field != exprFieldArg {
+		panic("actionlint.Cursor.InsertBefore/InsertAfter: current node is not a function call argument")
+	}
+	args := make([]ExprNode, 0, len(p.Args)+1)
+	args = append(args, p.Args[:at]...)
+	args = append(args, n)
+	args = append(args, p.Args[at:]...)
+	p.Args = args
+	setExprParent(n, p)
+}
+
+// setExprParent sets the unexported parent field of n. It exists because
+// Cursor and Apply need to re-establish parent pointers after a rewrite, the
+// same bookkeeping the parser does while building the tree.
+func setExprParent(n, parent ExprNode) {
+	switch n := n.(type) {
+	case *VariableNode:
+		n.parent = parent
+	case *NullNode:
+		n.parent = parent
+	case *BoolNode:
+		n.parent = parent
+	case *IntNode:
+		n.parent = parent
+	case *FloatNode:
+		n.parent = parent
+	case *StringNode:
+		n.parent = parent
+	case *ObjectDerefNode:
+		n.parent = parent
+	case *ArrayDerefNode:
+		n.parent = parent
+	case *IndexAccessNode:
+		n.parent = parent
+	case *NotOpNode:
+		n.parent = parent
+	case *CompareOpNode:
+		n.parent = parent
+	case *LogicalOpNode:
+		n.parent = parent
+	case *FuncCallNode:
+		n.parent = parent
+	}
+}
+
+// ApplyFunc is called for each node visited by Apply. Returning false from
+// pre skips the current node's children; post's return value is ignored.
+type ApplyFunc func(c *Cursor) bool
+
+// Apply traverses the expression syntax tree rooted at root, calling pre
+// before visiting a node's children and post after. Either may be nil.
+// Rewrites made through the Cursor take effect immediately. Apply returns
+// the (possibly replaced) root node.
+func Apply(root ExprNode, pre, post ApplyFunc) ExprNode {
+	c := &Cursor{node: root}
+	applyNode(c, pre, post)
+	return c.node
+}
+
+func applyNode(c *Cursor, pre, post ApplyFunc) {
+	if pre != nil && !pre(c) {
+		if post != nil {
+			post(c)
+		}
+		return
+	}
+
+	switch n := c.node.(type) {
+	case *ObjectDerefNode:
+		applyChild(n, exprFieldReceiver, 0, n.Receiver, pre, post)
+	case *ArrayDerefNode:
+		applyChild(n, exprFieldReceiver, 0, n.Receiver, pre, post)
+	case *IndexAccessNode:
+		// Index is visited before Operand, matching visitExprNode.
+		applyChild(n, exprFieldIndex, 0, n.Index, pre, post)
+		applyChild(n, exprFieldOperand, 0, n.Operand, pre, post)
+	case *NotOpNode:
+		applyChild(n, exprFieldOperand, 0, n.Operand, pre, post)
+	case *CompareOpNode:
+		applyChild(n, exprFieldLeft, 0, n.Left, pre, post)
+		applyChild(n, exprFieldRight, 0, n.Right, pre, post)
+	case *LogicalOpNode:
+		applyChild(n, exprFieldLeft, 0, n.Left, pre, post)
+		applyChild(n, exprFieldRight, 0, n.Right, pre, post)
+	case *FuncCallNode:
+		applyArgs(n, pre, post)
+	}
+
+	if post != nil {
+		post(c)
+	}
+}
+
+func applyChild(parent ExprNode, field exprField, index int, child ExprNode, pre, post ApplyFunc) {
+	cc := &Cursor{node: child, parent: parent, field: field, index: index}
+	applyNode(cc, pre, post)
+}
+
+// applyArgs visits each of n.Args, in position order, tolerating Delete,
+// InsertBefore and InsertAfter calls made while visiting them. It tracks
+// already-visited arguments by identity rather than by index, because a
+// Delete or InsertBefore on argument i shifts every later argument's
+// position: re-deriving "what's next" purely from an incrementing index
+// would either skip the argument that shifted into i's old slot or, worse,
+// visit an already-visited one a second time under a new index.
+func applyArgs(n *FuncCallNode, pre, post ApplyFunc) {
+	visited := make(map[ExprNode]bool, len(n.Args))
+	i := 0
+	for i < len(n.Args) {
+		child := n.Args[i]
+		cc := &Cursor{node: child, parent: n, field: exprFieldArg, index: i}
+		applyNode(cc, pre, post)
+		visited[cc.node] = true
+		for i < len(n.Args) && visited[n.Args[i]] {
+			i++
+		}
+	}
+}
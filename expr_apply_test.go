@@ -0,0 +1,104 @@
+package actionlint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testTok(v string) *Token {
+	return &Token{Value: v, Line: 1, Column: 1}
+}
+
+func TestApplyInsertBeforeVisitsNewArgument(t *testing.T) {
+	x := &VariableNode{Name: "x", tok: testTok("x")}
+	y := &VariableNode{Name: "y", tok: testTok("y")}
+	z := &VariableNode{Name: "z", tok: testTok("z")}
+	call := &FuncCallNode{Callee: "f", Args: []ExprNode{x, y, z}, tok: testTok("f")}
+	setExprParent(x, call)
+	setExprParent(y, call)
+	setExprParent(z, call)
+
+	inserted := false
+	var visited []string
+	Apply(call, nil, func(c *Cursor) bool {
+		v, ok := c.Node().(*VariableNode)
+		if !ok {
+			return true
+		}
+		visited = append(visited, v.Name)
+		if v.Name == "x" && !inserted {
+			inserted = true
+			c.InsertBefore(&VariableNode{Name: "w", tok: testTok("w")})
+		}
+		return true
+	})
+
+	// w is inserted while x is being visited, so it can't appear before x in
+	// visitation order; what matters is that it's visited exactly once, not
+	// skipped, and not confused with x under a stale index.
+	want := []string{"x", "w", "y", "z"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Fatalf("visited = %v, want %v", visited, want)
+	}
+	wantArgs := []string{"w", "x", "y", "z"}
+	for i, a := range call.Args {
+		if a.(*VariableNode).Name != wantArgs[i] {
+			t.Fatalf("Args = %v, want %v", call.Args, wantArgs)
+		}
+	}
+}
+
+func TestApplyInsertAfterVisitsNewArgument(t *testing.T) {
+	x := &VariableNode{Name: "x", tok: testTok("x")}
+	y := &VariableNode{Name: "y", tok: testTok("y")}
+	call := &FuncCallNode{Callee: "f", Args: []ExprNode{x, y}, tok: testTok("f")}
+	setExprParent(x, call)
+	setExprParent(y, call)
+
+	var visited []string
+	Apply(call, nil, func(c *Cursor) bool {
+		v, ok := c.Node().(*VariableNode)
+		if !ok {
+			return true
+		}
+		visited = append(visited, v.Name)
+		if v.Name == "x" {
+			c.InsertAfter(&VariableNode{Name: "w", tok: testTok("w")})
+		}
+		return true
+	})
+
+	want := []string{"x", "w", "y"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Fatalf("visited = %v, want %v", visited, want)
+	}
+}
+
+func TestApplyDeleteVisitsShiftedArgument(t *testing.T) {
+	x := &VariableNode{Name: "x", tok: testTok("x")}
+	y := &VariableNode{Name: "y", tok: testTok("y")}
+	call := &FuncCallNode{Callee: "f", Args: []ExprNode{x, y}, tok: testTok("f")}
+	setExprParent(x, call)
+	setExprParent(y, call)
+
+	var visited []string
+	Apply(call, nil, func(c *Cursor) bool {
+		v, ok := c.Node().(*VariableNode)
+		if !ok {
+			return true
+		}
+		visited = append(visited, v.Name)
+		if v.Name == "x" {
+			c.Delete()
+		}
+		return true
+	})
+
+	if len(call.Args) != 1 || call.Args[0] != ExprNode(y) {
+		t.Fatalf("Args after delete = %v", call.Args)
+	}
+	want := []string{"x", "y"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Fatalf("visited = %v, want %v", visited, want)
+	}
+}
@@ -5,6 +5,10 @@ package actionlint
 type ExprNode interface {
 	// Token returns the first token of the node. This method is useful to get position of this node.
 	Token() *Token
+	// End returns the last token of the node. Together with Token(), this gives the span of the
+	// node, which is useful for diagnostics that need to highlight a whole subexpression rather
+	// than just its start.
+	End() *Token
 	// Parent returns the parent node of this node.
 	Parent() ExprNode
 }
@@ -24,6 +28,11 @@ func (n *VariableNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *VariableNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *VariableNode) Parent() ExprNode {
 	return n.parent
@@ -42,6 +51,11 @@ func (n *NullNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *NullNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *NullNode) Parent() ExprNode {
 	return n.parent
@@ -60,6 +74,11 @@ func (n *BoolNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *BoolNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *BoolNode) Parent() ExprNode {
 	return n.parent
@@ -78,6 +97,11 @@ func (n *IntNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *IntNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *IntNode) Parent() ExprNode {
 	return n.parent
@@ -96,6 +120,11 @@ func (n *FloatNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *FloatNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *FloatNode) Parent() ExprNode {
 	return n.parent
@@ -115,11 +144,25 @@ func (n *StringNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node.
+func (n *StringNode) End() *Token {
+	return n.tok
+}
+
 // Parent returns the parent node of this node.
 func (n *StringNode) Parent() ExprNode {
 	return n.parent
 }
 
+// endOffset returns a synthetic, zero-width token positioned cols characters
+// past the true end of base (i.e. past base.Column+len(base.Value)). It is
+// used by End() fallbacks below to account for trailing syntax a node does
+// not yet carry a real token for, such as a closing ')'/']' or the '.*' of
+// an array deref.
+func endOffset(base *Token, cols int) *Token {
+	return &Token{Kind: base.Kind, Line: base.Line, Column: base.Column + len(base.Value) + cols}
+}
+
 // Operators
 
 // ObjectDerefNode represents property dereference of object like 'foo.bar'.
@@ -128,7 +171,10 @@ type ObjectDerefNode struct {
 	Receiver ExprNode
 	// Property is a name of property to access.
 	Property string
-	parent   ExprNode
+	// propTok is the token of Property. It is nil until the parser is updated to record it, in
+	// which case End() falls back to a position derived from the receiver's end.
+	propTok *Token
+	parent  ExprNode
 }
 
 // Token returns the first token of the node. This method is useful to get position of this node.
@@ -136,6 +182,17 @@ func (n *ObjectDerefNode) Token() *Token {
 	return n.Receiver.Token()
 }
 
+// End returns the last token of the node. This is propTok when the parser
+// has recorded it; today the parser never does, so this always falls back
+// to an approximate position derived from the receiver's end plus the
+// length of Property.
+func (n *ObjectDerefNode) End() *Token {
+	if n.propTok != nil {
+		return n.propTok
+	}
+	return endOffset(n.Receiver.End(), 1+len(n.Property)) // '.' + property name
+}
+
 // Parent returns the parent node of this node.
 func (n *ObjectDerefNode) Parent() ExprNode {
 	return n.parent
@@ -145,7 +202,10 @@ func (n *ObjectDerefNode) Parent() ExprNode {
 type ArrayDerefNode struct {
 	// Receiver is an expression at receiver of array element dereference.
 	Receiver ExprNode
-	parent   ExprNode
+	// starTok is the token of the '*'. It is nil until the parser is updated to record it, in
+	// which case End() falls back to a position derived from the receiver's end.
+	starTok *Token
+	parent  ExprNode
 }
 
 // Token returns the first token of the node. This method is useful to get position of this node.
@@ -153,6 +213,16 @@ func (n *ArrayDerefNode) Token() *Token {
 	return n.Receiver.Token()
 }
 
+// End returns the last token of the node. This is starTok when the parser
+// has recorded it; today the parser never does, so this always falls back
+// to an approximate position derived from the receiver's end.
+func (n *ArrayDerefNode) End() *Token {
+	if n.starTok != nil {
+		return n.starTok
+	}
+	return endOffset(n.Receiver.End(), 2) // '.*'
+}
+
 // Parent returns the parent node of this node.
 func (n *ArrayDerefNode) Parent() ExprNode {
 	return n.parent
@@ -164,7 +234,10 @@ type IndexAccessNode struct {
 	// Operand is an expression at operand of index access, which should be array or object.
 	Operand ExprNode
 	// Index is an expression at index, which should be integer or string.
-	Index  ExprNode
+	Index ExprNode
+	// rbrack is the token of the closing ']'. It is nil until the parser is updated to record
+	// it, in which case End() falls back to a position derived from the index's end.
+	rbrack *Token
 	parent ExprNode
 }
 
@@ -173,6 +246,17 @@ func (n *IndexAccessNode) Token() *Token {
 	return n.Operand.Token()
 }
 
+// End returns the last token of the node. This is rbrack, the closing ']',
+// when the parser has recorded it; today the parser never does, so this
+// always falls back to an approximate position derived from the index's
+// end.
+func (n *IndexAccessNode) End() *Token {
+	if n.rbrack != nil {
+		return n.rbrack
+	}
+	return endOffset(n.Index.End(), 1) // ']'
+}
+
 // Parent returns the parent node of this node.
 func (n *IndexAccessNode) Parent() ExprNode {
 	return n.parent
@@ -193,6 +277,11 @@ func (n *NotOpNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node, which is the end of Operand.
+func (n *NotOpNode) End() *Token {
+	return n.Operand.End()
+}
+
 // Parent returns the parent node of this node.
 func (n *NotOpNode) Parent() ExprNode {
 	return n.parent
@@ -258,6 +347,11 @@ func (n *CompareOpNode) Token() *Token {
 	return n.Left.Token()
 }
 
+// End returns the last token of the node, which is the end of Right.
+func (n *CompareOpNode) End() *Token {
+	return n.Right.End()
+}
+
 // Parent returns the parent node of this node.
 func (n *CompareOpNode) Parent() ExprNode {
 	return n.parent
@@ -302,6 +396,11 @@ func (n *LogicalOpNode) Token() *Token {
 	return n.Left.Token()
 }
 
+// End returns the last token of the node, which is the end of Right.
+func (n *LogicalOpNode) End() *Token {
+	return n.Right.End()
+}
+
 // Parent returns the parent node of this node.
 func (n *LogicalOpNode) Parent() ExprNode {
 	return n.parent
@@ -314,8 +413,12 @@ type FuncCallNode struct {
 	// functions can be called.
 	Callee string
 	// Args is arguments of the function call.
-	Args   []ExprNode
-	tok    *Token
+	Args []ExprNode
+	tok  *Token
+	// rparen is the token of the closing ')'. It is nil until the parser is updated to record
+	// it, in which case End() falls back to a position derived from the last argument's end, or
+	// from the callee's token when there are no arguments.
+	rparen *Token
 	parent ExprNode
 }
 
@@ -324,6 +427,20 @@ func (n *FuncCallNode) Token() *Token {
 	return n.tok
 }
 
+// End returns the last token of the node. This is rparen, the closing ')',
+// when the parser has recorded it; today the parser never does, so this
+// always falls back to an approximate position derived from the last
+// argument's end, or the callee's token when there are no arguments.
+func (n *FuncCallNode) End() *Token {
+	if n.rparen != nil {
+		return n.rparen
+	}
+	if len(n.Args) > 0 {
+		return endOffset(n.Args[len(n.Args)-1].End(), 1) // ')'
+	}
+	return endOffset(n.tok, 2) // '(' + ')'
+}
+
 // Parent returns the parent node of this node.
 func (n *FuncCallNode) Parent() ExprNode {
 	return n.parent
@@ -0,0 +1,33 @@
+package actionlint
+
+import "testing"
+
+func TestEndFallsBackToSyntheticTrailingToken(t *testing.T) {
+	recv := &VariableNode{Name: "github", tok: testTok("github")}
+	deref := &ObjectDerefNode{Receiver: recv, Property: "event_name"}
+	setExprParent(recv, deref)
+
+	end := deref.End()
+	if end.Line != recv.tok.Line {
+		t.Fatalf("End().Line = %d, want %d", end.Line, recv.tok.Line)
+	}
+	wantCol := recv.tok.Column + len(recv.tok.Value) + 1 + len(deref.Property)
+	if end.Column != wantCol {
+		t.Errorf("End().Column = %d, want %d", end.Column, wantCol)
+	}
+}
+
+func TestEndPrefersRecordedToken(t *testing.T) {
+	call := &FuncCallNode{Callee: "f", tok: testTok("f"), rparen: testTok(")")}
+	if call.End() != call.rparen {
+		t.Errorf("End() = %v, want rparen", call.End())
+	}
+}
+
+func TestSpanReturnsTokenAndEnd(t *testing.T) {
+	n := &VariableNode{Name: "github", tok: testTok("github")}
+	start, end := Span(n)
+	if start != n.tok || end != n.tok {
+		t.Errorf("Span() = (%v, %v), want (%v, %v)", start, end, n.tok, n.tok)
+	}
+}
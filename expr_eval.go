@@ -0,0 +1,506 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ValueKind is the kind of a Value, following the value types defined by the
+// expression grammar: null, boolean, number (split here into int and float
+// the way the AST's literal nodes already are), string, object and array.
+type ValueKind int
+
+const (
+	// ValueKindNull is the kind for a null value.
+	ValueKindNull ValueKind = iota
+	// ValueKindBool is the kind for a boolean value.
+	ValueKindBool
+	// ValueKindInt is the kind for an integer number value.
+	ValueKindInt
+	// ValueKindFloat is the kind for a floating point number value.
+	ValueKindFloat
+	// ValueKindString is the kind for a string value.
+	ValueKindString
+	// ValueKindObject is the kind for an object value.
+	ValueKindObject
+	// ValueKindArray is the kind for an array value.
+	ValueKindArray
+)
+
+// Value is the result of evaluating an ExprNode with Eval. Exactly one of
+// Bool, Int, Float, Str, Object or Array is meaningful, selected by Kind.
+type Value struct {
+	Kind   ValueKind
+	Bool   bool
+	Int    int
+	Float  float64
+	Str    string
+	Object map[string]Value
+	Array  []Value
+}
+
+// NullValue returns the null value.
+func NullValue() Value { return Value{Kind: ValueKindNull} }
+
+// BoolValue returns a boolean value.
+func BoolValue(b bool) Value { return Value{Kind: ValueKindBool, Bool: b} }
+
+// IntValue returns an integer number value.
+func IntValue(i int) Value { return Value{Kind: ValueKindInt, Int: i} }
+
+// FloatValue returns a floating point number value.
+func FloatValue(f float64) Value { return Value{Kind: ValueKindFloat, Float: f} }
+
+// StringValue returns a string value.
+func StringValue(s string) Value { return Value{Kind: ValueKindString, Str: s} }
+
+// Eval evaluates the expression syntax tree rooted at n, resolving
+// VariableNode references against env (e.g. env["github"] for a `github.*`
+// access). It implements the coercion rules used by GitHub Actions
+// expressions rather than Go's own: == and != coerce through numbers except
+// when both operands are strings, in which case the comparison is
+// case-insensitive; && and || return whichever operand was last evaluated
+// rather than a bool; ! coerces its operand via truthiness; numeric
+// comparisons coerce strings with ParseFloat, producing NaN (which compares
+// false against everything) on failure; and indexing or dereferencing a
+// property that is absent from a known object yields Null rather than an
+// error.
+//
+// Eval returns an error when the tree cannot be evaluated at all: a
+// VariableNode whose name is not in env (this is how github.*, secrets.*,
+// env.* etc. stay symbolic rather than folding to null), a function call
+// to anything other than the small set of pure builtins implemented below,
+// a builtin called with the wrong number of arguments, a format() string
+// with an unmatched brace or invalid placeholder, or a fromJSON() argument
+// that is not valid JSON.
+func Eval(n ExprNode, env map[string]any) (Value, error) {
+	switch n := n.(type) {
+	case *NullNode:
+		return NullValue(), nil
+	case *BoolNode:
+		return BoolValue(n.Value), nil
+	case *IntNode:
+		return IntValue(n.Value), nil
+	case *FloatNode:
+		return FloatValue(n.Value), nil
+	case *StringNode:
+		return StringValue(n.Value), nil
+	case *VariableNode:
+		v, ok := env[n.Name]
+		if !ok {
+			return Value{}, fmt.Errorf("%q is not defined", n.Name)
+		}
+		return fromAny(v), nil
+	case *ObjectDerefNode:
+		r, err := Eval(n.Receiver, env)
+		if err != nil {
+			return Value{}, err
+		}
+		return objectProp(r, n.Property), nil
+	case *ArrayDerefNode:
+		// The '*' filter evaluates to the receiver itself. Later property or
+		// index access is not distributed over its elements: dereferencing a
+		// property of the resulting array yields Null.
+		return Eval(n.Receiver, env)
+	case *IndexAccessNode:
+		r, err := Eval(n.Operand, env)
+		if err != nil {
+			return Value{}, err
+		}
+		i, err := Eval(n.Index, env)
+		if err != nil {
+			return Value{}, err
+		}
+		return indexValue(r, i), nil
+	case *NotOpNode:
+		v, err := Eval(n.Operand, env)
+		if err != nil {
+			return Value{}, err
+		}
+		return BoolValue(!truthy(v)), nil
+	case *CompareOpNode:
+		return evalCompare(n, env)
+	case *LogicalOpNode:
+		return evalLogical(n, env)
+	case *FuncCallNode:
+		return evalFuncCall(n, env)
+	default:
+		return Value{}, fmt.Errorf("actionlint.Eval: unknown expression node type %T", n)
+	}
+}
+
+func evalCompare(n *CompareOpNode, env map[string]any) (Value, error) {
+	l, err := Eval(n.Left, env)
+	if err != nil {
+		return Value{}, err
+	}
+	r, err := Eval(n.Right, env)
+	if err != nil {
+		return Value{}, err
+	}
+
+	if n.Kind.IsEqualityOp() {
+		eq := valuesEqual(l, r)
+		if n.Kind == CompareOpNodeKindNotEq {
+			eq = !eq
+		}
+		return BoolValue(eq), nil
+	}
+
+	lf, rf := toNumber(l), toNumber(r)
+	switch n.Kind {
+	case CompareOpNodeKindLess:
+		return BoolValue(lf < rf), nil
+	case CompareOpNodeKindLessEq:
+		return BoolValue(lf <= rf), nil
+	case CompareOpNodeKindGreater:
+		return BoolValue(lf > rf), nil
+	case CompareOpNodeKindGreaterEq:
+		return BoolValue(lf >= rf), nil
+	default:
+		return Value{}, fmt.Errorf("actionlint.Eval: unknown compare operator %q", n.Kind)
+	}
+}
+
+func valuesEqual(l, r Value) bool {
+	if l.Kind == ValueKindString && r.Kind == ValueKindString {
+		return strings.EqualFold(l.Str, r.Str)
+	}
+	return toNumber(l) == toNumber(r)
+}
+
+func evalLogical(n *LogicalOpNode, env map[string]any) (Value, error) {
+	l, err := Eval(n.Left, env)
+	if err != nil {
+		return Value{}, err
+	}
+	switch n.Kind {
+	case LogicalOpNodeKindAnd:
+		if !truthy(l) {
+			return l, nil
+		}
+	case LogicalOpNodeKindOr:
+		if truthy(l) {
+			return l, nil
+		}
+	}
+	return Eval(n.Right, env)
+}
+
+func evalFuncCall(n *FuncCallNode, env map[string]any) (Value, error) {
+	args := make([]Value, len(n.Args))
+	for i, a := range n.Args {
+		v, err := Eval(a, env)
+		if err != nil {
+			return Value{}, err
+		}
+		args[i] = v
+	}
+
+	switch strings.ToLower(n.Callee) {
+	case "contains":
+		if len(args) != 2 {
+			return Value{}, fmt.Errorf("contains() takes 2 arguments but got %d", len(args))
+		}
+		return BoolValue(evalContains(args[0], args[1])), nil
+	case "startswith":
+		if len(args) != 2 {
+			return Value{}, fmt.Errorf("startsWith() takes 2 arguments but got %d", len(args))
+		}
+		return BoolValue(strings.HasPrefix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))), nil
+	case "endswith":
+		if len(args) != 2 {
+			return Value{}, fmt.Errorf("endsWith() takes 2 arguments but got %d", len(args))
+		}
+		return BoolValue(strings.HasSuffix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))), nil
+	case "format":
+		if len(args) == 0 {
+			return Value{}, fmt.Errorf("format() takes at least 1 argument but got 0")
+		}
+		s, err := evalFormat(toString(args[0]), args[1:])
+		if err != nil {
+			return Value{}, err
+		}
+		return StringValue(s), nil
+	case "join":
+		if len(args) != 1 && len(args) != 2 {
+			return Value{}, fmt.Errorf("join() takes 1 or 2 arguments but got %d", len(args))
+		}
+		sep := ","
+		if len(args) == 2 {
+			sep = toString(args[1])
+		}
+		return StringValue(evalJoin(args[0], sep)), nil
+	case "tojson":
+		if len(args) != 1 {
+			return Value{}, fmt.Errorf("toJSON() takes 1 argument but got %d", len(args))
+		}
+		b, err := json.Marshal(toAny(args[0]))
+		if err != nil {
+			return Value{}, err
+		}
+		return StringValue(string(b)), nil
+	case "fromjson":
+		if len(args) != 1 {
+			return Value{}, fmt.Errorf("fromJSON() takes 1 argument but got %d", len(args))
+		}
+		var v any
+		if err := json.Unmarshal([]byte(toString(args[0])), &v); err != nil {
+			return Value{}, err
+		}
+		return fromAny(v), nil
+	default:
+		return Value{}, fmt.Errorf("%q is not a constant-foldable function", n.Callee)
+	}
+}
+
+func evalContains(haystack, needle Value) bool {
+	if haystack.Kind == ValueKindArray {
+		for _, e := range haystack.Array {
+			if valuesEqual(e, needle) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(strings.ToLower(toString(haystack)), strings.ToLower(toString(needle)))
+}
+
+// evalFormat implements format()'s replacement syntax: "{{" and "}}" are
+// escapes for a literal brace, "{n}" is a placeholder, and any other lone
+// '{' or '}' is an error rather than something to guess at.
+func evalFormat(format string, args []Value) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		switch c := format[i]; c {
+		case '{':
+			if i+1 < len(format) && format[i+1] == '{' {
+				b.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("format string %q has an unmatched '{'", format)
+			}
+			numStr := format[i+1 : i+end]
+			idx, err := strconv.Atoi(numStr)
+			if err != nil || idx < 0 || idx >= len(args) {
+				return "", fmt.Errorf("format string %q has an invalid placeholder {%s}", format, numStr)
+			}
+			b.WriteString(toString(args[idx]))
+			i += end
+		case '}':
+			if i+1 < len(format) && format[i+1] == '}' {
+				b.WriteByte('}')
+				i++
+				continue
+			}
+			return "", fmt.Errorf("format string %q has an unmatched '}'", format)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+func evalJoin(v Value, sep string) string {
+	if v.Kind != ValueKindArray {
+		return toString(v)
+	}
+	ss := make([]string, len(v.Array))
+	for i, e := range v.Array {
+		ss[i] = toString(e)
+	}
+	return strings.Join(ss, sep)
+}
+
+func objectProp(v Value, prop string) Value {
+	if v.Kind != ValueKindObject {
+		return NullValue()
+	}
+	p, ok := v.Object[prop]
+	if !ok {
+		return NullValue()
+	}
+	return p
+}
+
+func indexValue(v, idx Value) Value {
+	switch v.Kind {
+	case ValueKindArray:
+		i := int(toNumber(idx))
+		if i < 0 || i >= len(v.Array) {
+			return NullValue()
+		}
+		return v.Array[i]
+	case ValueKindObject:
+		return objectProp(v, toString(idx))
+	default:
+		return NullValue()
+	}
+}
+
+func toNumber(v Value) float64 {
+	switch v.Kind {
+	case ValueKindNull:
+		return 0
+	case ValueKindBool:
+		if v.Bool {
+			return 1
+		}
+		return 0
+	case ValueKindInt:
+		return float64(v.Int)
+	case ValueKindFloat:
+		return v.Float
+	case ValueKindString:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.Str), 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return f
+	default:
+		return math.NaN()
+	}
+}
+
+func truthy(v Value) bool {
+	switch v.Kind {
+	case ValueKindNull:
+		return false
+	case ValueKindBool:
+		return v.Bool
+	case ValueKindInt:
+		return v.Int != 0
+	case ValueKindFloat:
+		return v.Float != 0 && !math.IsNaN(v.Float)
+	case ValueKindString:
+		return v.Str != ""
+	default:
+		return true
+	}
+}
+
+func toString(v Value) string {
+	switch v.Kind {
+	case ValueKindNull:
+		return ""
+	case ValueKindBool:
+		return strconv.FormatBool(v.Bool)
+	case ValueKindInt:
+		return strconv.Itoa(v.Int)
+	case ValueKindFloat:
+		return formatFloat(v.Float)
+	case ValueKindString:
+		return v.Str
+	default:
+		b, _ := json.Marshal(toAny(v))
+		return string(b)
+	}
+}
+
+// toAny converts v to a plain Go value suitable for json.Marshal.
+func toAny(v Value) any {
+	switch v.Kind {
+	case ValueKindNull:
+		return nil
+	case ValueKindBool:
+		return v.Bool
+	case ValueKindInt:
+		return v.Int
+	case ValueKindFloat:
+		return v.Float
+	case ValueKindString:
+		return v.Str
+	case ValueKindObject:
+		m := make(map[string]any, len(v.Object))
+		for k, e := range v.Object {
+			m[k] = toAny(e)
+		}
+		return m
+	case ValueKindArray:
+		a := make([]any, len(v.Array))
+		for i, e := range v.Array {
+			a[i] = toAny(e)
+		}
+		return a
+	default:
+		return nil
+	}
+}
+
+// fromAny converts a decoded JSON value (or a value placed into an Eval env
+// map by hand) into a Value.
+func fromAny(v any) Value {
+	switch v := v.(type) {
+	case nil:
+		return NullValue()
+	case bool:
+		return BoolValue(v)
+	case int:
+		return IntValue(v)
+	case float64:
+		return FloatValue(v)
+	case string:
+		return StringValue(v)
+	case map[string]any:
+		m := make(map[string]Value, len(v))
+		for k, e := range v {
+			m[k] = fromAny(e)
+		}
+		return Value{Kind: ValueKindObject, Object: m}
+	case []any:
+		a := make([]Value, len(v))
+		for i, e := range v {
+			a[i] = fromAny(e)
+		}
+		return Value{Kind: ValueKindArray, Array: a}
+	default:
+		return NullValue()
+	}
+}
+
+// valueToNode converts v into a literal ExprNode carrying tok as its
+// position, or nil when v has no literal representation (objects and
+// arrays, which the expression grammar has no literal syntax for).
+func valueToNode(v Value, tok *Token) ExprNode {
+	switch v.Kind {
+	case ValueKindNull:
+		return &NullNode{tok: tok}
+	case ValueKindBool:
+		return &BoolNode{Value: v.Bool, tok: tok}
+	case ValueKindInt:
+		return &IntNode{Value: v.Int, tok: tok}
+	case ValueKindFloat:
+		return &FloatNode{Value: v.Float, tok: tok}
+	case ValueKindString:
+		return &StringNode{Value: v.Str, tok: tok}
+	default:
+		return nil
+	}
+}
+
+// FoldConstants rewrites n bottom-up, replacing any subexpression that Eval
+// can evaluate against an empty environment with a literal node holding that
+// result. Subexpressions referencing a context (github, env, secrets, ...)
+// fail to evaluate and are left as-is, except where a &&/|| short-circuits
+// before reaching them (e.g. "false && github.event_name == 'push'" folds to
+// false without needing to know the event name). This turns tautologies and
+// impossible guards like "if: ${{ false }}" into literal nodes a caller can
+// flag, without having to special-case every way they might be spelled.
+func FoldConstants(n ExprNode) ExprNode {
+	return Apply(n, nil, func(c *Cursor) bool {
+		v, err := Eval(c.Node(), nil)
+		if err != nil {
+			return true
+		}
+		if lit := valueToNode(v, c.Node().Token()); lit != nil {
+			c.Replace(lit)
+		}
+		return true
+	})
+}
@@ -0,0 +1,61 @@
+package actionlint
+
+import "testing"
+
+func TestEvalFormatEscapesBraces(t *testing.T) {
+	got, err := evalFormat("{{{0}}}", []Value{StringValue("x")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "{x}"; got != want {
+		t.Errorf("evalFormat() = %q, want %q", got, want)
+	}
+}
+
+func TestEvalFormatErrorsOnUnmatchedBrace(t *testing.T) {
+	if _, err := evalFormat("{0", []Value{StringValue("x")}); err == nil {
+		t.Fatal("expected an error for an unmatched '{', got nil")
+	}
+	if _, err := evalFormat("0}", nil); err == nil {
+		t.Fatal("expected an error for an unmatched '}', got nil")
+	}
+}
+
+func TestEvalLogicalShortCircuitFoldsWithoutSymbolicSide(t *testing.T) {
+	n := &LogicalOpNode{
+		Kind: LogicalOpNodeKindAnd,
+		Left: &BoolNode{Value: false, tok: testTok("false")},
+		Right: &CompareOpNode{
+			Kind:  CompareOpNodeKindEq,
+			Left:  &VariableNode{Name: "github", tok: testTok("github")},
+			Right: &StringNode{Value: "push", tok: testTok("'push'")},
+		},
+	}
+	v, err := Eval(n, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Kind != ValueKindBool || v.Bool != false {
+		t.Errorf("Eval() = %+v, want false", v)
+	}
+}
+
+func TestEvalUnknownVariableErrors(t *testing.T) {
+	n := &VariableNode{Name: "github", tok: testTok("github")}
+	if _, err := Eval(n, nil); err == nil {
+		t.Fatal("expected an error for an undefined variable, got nil")
+	}
+}
+
+func TestFoldConstantsFoldsTautology(t *testing.T) {
+	n := &CompareOpNode{
+		Kind:  CompareOpNodeKindEq,
+		Left:  &IntNode{Value: 1, tok: testTok("1")},
+		Right: &IntNode{Value: 1, tok: testTok("1")},
+	}
+	folded := FoldConstants(n)
+	b, ok := folded.(*BoolNode)
+	if !ok || !b.Value {
+		t.Errorf("FoldConstants() = %+v, want *BoolNode{Value: true}", folded)
+	}
+}
@@ -0,0 +1,234 @@
+package actionlint
+
+import (
+	"strconv"
+	"strings"
+)
+
+// exprPrec returns the precedence of the operator at the root of n, from
+// loosest (1, ||) to tightest (6, primary expressions).
+func exprPrec(n ExprNode) int {
+	switch n := n.(type) {
+	case *LogicalOpNode:
+		if n.Kind == LogicalOpNodeKindOr {
+			return 1
+		}
+		return 2
+	case *CompareOpNode:
+		return 3
+	case *NotOpNode:
+		return 4
+	case *ObjectDerefNode, *ArrayDerefNode, *IndexAccessNode:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// Format renders n back into a syntactically valid expression, adding
+// parentheses only where needed to preserve the tree's structure. Parsing
+// the result again produces an equivalent tree, though not necessarily
+// byte-for-byte the original source.
+func Format(n ExprNode) string {
+	var b strings.Builder
+	writeExprNode(&b, n)
+	return b.String()
+}
+
+func writeExprChild(b *strings.Builder, child, parent ExprNode) {
+	if exprPrec(child) < exprPrec(parent) {
+		b.WriteByte('(')
+		writeExprNode(b, child)
+		b.WriteByte(')')
+		return
+	}
+	writeExprNode(b, child)
+}
+
+// writeExprNonAssocRightChild writes the right operand of a comparison.
+// Comparisons can't be chained, so unlike writeExprChild it parenthesizes
+// child whenever its precedence is <= the parent's, not just <: otherwise
+// "a < (b < c)" would print as "a < b < c", which re-parses as the
+// differently-grouped "(a < b) < c".
+func writeExprNonAssocRightChild(b *strings.Builder, child, parent ExprNode) {
+	if exprPrec(child) <= exprPrec(parent) {
+		b.WriteByte('(')
+		writeExprNode(b, child)
+		b.WriteByte(')')
+		return
+	}
+	writeExprNode(b, child)
+}
+
+func writeExprNode(b *strings.Builder, n ExprNode) {
+	switch n := n.(type) {
+	case *VariableNode:
+		b.WriteString(n.Name)
+	case *NullNode:
+		b.WriteString("null")
+	case *BoolNode:
+		if n.Value {
+			b.WriteString("true")
+		} else {
+			b.WriteString("false")
+		}
+	case *IntNode:
+		b.WriteString(strconv.Itoa(n.Value))
+	case *FloatNode:
+		b.WriteString(formatFloat(n.Value))
+	case *StringNode:
+		b.WriteByte('\'')
+		b.WriteString(strings.ReplaceAll(n.Value, "'", "''"))
+		b.WriteByte('\'')
+	case *ObjectDerefNode:
+		writeExprChild(b, n.Receiver, n)
+		b.WriteByte('.')
+		b.WriteString(n.Property)
+	case *ArrayDerefNode:
+		writeExprChild(b, n.Receiver, n)
+		b.WriteString(".*")
+	case *IndexAccessNode:
+		writeExprChild(b, n.Operand, n)
+		b.WriteByte('[')
+		writeExprNode(b, n.Index)
+		b.WriteByte(']')
+	case *NotOpNode:
+		b.WriteByte('!')
+		writeExprChild(b, n.Operand, n)
+	case *CompareOpNode:
+		writeExprChild(b, n.Left, n)
+		b.WriteByte(' ')
+		b.WriteString(n.Kind.String())
+		b.WriteByte(' ')
+		writeExprNonAssocRightChild(b, n.Right, n)
+	case *LogicalOpNode:
+		writeExprChild(b, n.Left, n)
+		b.WriteByte(' ')
+		b.WriteString(n.Kind.String())
+		b.WriteByte(' ')
+		writeExprChild(b, n.Right, n)
+	case *FuncCallNode:
+		b.WriteString(n.Callee)
+		b.WriteByte('(')
+		for i, a := range n.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeExprNode(b, a)
+		}
+		b.WriteByte(')')
+	}
+}
+
+// formatFloat formats f the way the expression grammar expects float
+// literals to look, e.g. "1.5", "-1", "1e+10".
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+const (
+	// prettyMaxWidth is the line width Pretty tries to keep a rendered
+	// subexpression within before breaking it onto multiple lines.
+	prettyMaxWidth = 80
+	// prettyIndentWidth is the number of spaces used per indent level.
+	prettyIndentWidth = 2
+)
+
+// Pretty renders n the same way Format does when it fits within
+// prettyMaxWidth, but breaks long &&/|| chains and function-call argument
+// lists onto their own indented lines when it does not. indent is the
+// starting indent level, in units of prettyIndentWidth spaces; pass 0 at the
+// top level.
+func Pretty(n ExprNode, indent int) string {
+	flat := Format(n)
+	if len(flat)+indent*prettyIndentWidth <= prettyMaxWidth {
+		return flat
+	}
+	switch n := n.(type) {
+	case *LogicalOpNode:
+		return prettyLogicalChain(n, indent)
+	case *FuncCallNode:
+		return prettyFuncCall(n, indent)
+	default:
+		return flat
+	}
+}
+
+// flattenLogicalChain collects the operands of a left-leaning chain of the
+// same logical operator, e.g. "a && b && c" becomes [a, b, c] rather than
+// [LogicalOpNode(a, b), c].
+func flattenLogicalChain(n *LogicalOpNode) []ExprNode {
+	var operands []ExprNode
+	var walk func(ExprNode)
+	walk = func(e ExprNode) {
+		if l, ok := e.(*LogicalOpNode); ok && l.Kind == n.Kind {
+			walk(l.Left)
+			walk(l.Right)
+			return
+		}
+		operands = append(operands, e)
+	}
+	walk(n)
+	return operands
+}
+
+func prettyLogicalChain(n *LogicalOpNode, indent int) string {
+	operands := flattenLogicalChain(n)
+	pad := strings.Repeat(" ", indent*prettyIndentWidth)
+	childPad := strings.Repeat(" ", (indent+1)*prettyIndentWidth)
+
+	var b strings.Builder
+	for i, o := range operands {
+		s := Pretty(o, indent+1)
+		if exprPrec(o) < exprPrec(n) {
+			s = parenthesizeOperand(s, childPad)
+		}
+		if i == 0 {
+			b.WriteString(childPad)
+			b.WriteString(s)
+			continue
+		}
+		b.WriteByte('\n')
+		b.WriteString(pad)
+		b.WriteString(n.Kind.String())
+		b.WriteByte(' ')
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// parenthesizeOperand wraps s, the pretty-printed rendering of a logical
+// chain operand, in parens. A single-line s is wrapped in place. A
+// multi-line s already carries its own correct indentation on every line
+// (prettyLogicalChain builds it that way so it can be inlined after an
+// operator), so concatenating "(" directly onto its first line would just
+// push stray spaces after the paren, and appending ")" after its last line
+// would leave the closing paren dangling off that line's text. Instead the
+// parens go on their own lines, indented to align with childPad, the level
+// the operand would otherwise have started at.
+func parenthesizeOperand(s, childPad string) string {
+	if !strings.Contains(s, "\n") {
+		return "(" + s + ")"
+	}
+	return "(\n" + s + "\n" + childPad + ")"
+}
+
+func prettyFuncCall(n *FuncCallNode, indent int) string {
+	pad := strings.Repeat(" ", indent*prettyIndentWidth)
+	childPad := strings.Repeat(" ", (indent+1)*prettyIndentWidth)
+
+	var b strings.Builder
+	b.WriteString(n.Callee)
+	b.WriteString("(\n")
+	for i, a := range n.Args {
+		b.WriteString(childPad)
+		b.WriteString(Pretty(a, indent+1))
+		if i < len(n.Args)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(pad)
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package actionlint
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		node ExprNode
+		want string
+	}{
+		{
+			"variable",
+			&VariableNode{Name: "github", tok: testTok("github")},
+			"github",
+		},
+		{
+			"string literal escapes quotes",
+			&StringNode{Value: "it's", tok: testTok("'it''s'")},
+			"'it''s'",
+		},
+		{
+			"logical chain of the same operator needs no parens",
+			&LogicalOpNode{
+				Kind:  LogicalOpNodeKindAnd,
+				Left:  &BoolNode{Value: true, tok: testTok("true")},
+				Right: &BoolNode{Value: false, tok: testTok("false")},
+			},
+			"true && false",
+		},
+		{
+			// (a < b) < c, the only way the parser would ever produce a
+			// left-nested CompareOpNode, round-trips without parens because
+			// the grammar's default association is already left-to-right.
+			"left-nested comparison needs no parens",
+			&CompareOpNode{
+				Kind: CompareOpNodeKindLess,
+				Left: &CompareOpNode{
+					Kind:  CompareOpNodeKindLess,
+					Left:  &VariableNode{Name: "a", tok: testTok("a")},
+					Right: &VariableNode{Name: "b", tok: testTok("b")},
+				},
+				Right: &VariableNode{Name: "c", tok: testTok("c")},
+			},
+			"a < b < c",
+		},
+		{
+			// a < (b < c) must keep its parens: without them it would print
+			// as "a < b < c", which re-parses as "(a < b) < c".
+			"right-nested comparison keeps parens",
+			&CompareOpNode{
+				Kind: CompareOpNodeKindLess,
+				Left: &VariableNode{Name: "a", tok: testTok("a")},
+				Right: &CompareOpNode{
+					Kind:  CompareOpNodeKindLess,
+					Left:  &VariableNode{Name: "b", tok: testTok("b")},
+					Right: &VariableNode{Name: "c", tok: testTok("c")},
+				},
+			},
+			"a < (b < c)",
+		},
+		{
+			"function call",
+			&FuncCallNode{
+				Callee: "contains",
+				Args: []ExprNode{
+					&VariableNode{Name: "github", tok: testTok("github")},
+					&StringNode{Value: "x", tok: testTok("'x'")},
+				},
+				tok: testTok("contains"),
+			},
+			"contains(github, 'x')",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Format(tc.node); got != tc.want {
+				t.Errorf("Format() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func varNode(name string) *VariableNode {
+	return &VariableNode{Name: name, tok: testTok(name)}
+}
+
+func TestPrettyReturnsFlatFormatWhenItFits(t *testing.T) {
+	n := &LogicalOpNode{Kind: LogicalOpNodeKindAnd, Left: varNode("a"), Right: varNode("b")}
+	if got, want := Pretty(n, 0), Format(n); got != want {
+		t.Errorf("Pretty() = %q, want %q", got, want)
+	}
+}
+
+func TestPrettyFuncCallBreaksLongArgsOntoLines(t *testing.T) {
+	n := &FuncCallNode{
+		Callee: "format",
+		Args: []ExprNode{
+			varNode("firstVeryLongArgumentNameAAAAAAAAAAAAAAAAAAAA"),
+			varNode("secondVeryLongArgumentNameBBBBBBBBBBBBBBBBBBBB"),
+		},
+		tok: testTok("format"),
+	}
+	want := "format(\n" +
+		"  firstVeryLongArgumentNameAAAAAAAAAAAAAAAAAAAA,\n" +
+		"  secondVeryLongArgumentNameBBBBBBBBBBBBBBBBBBBB\n" +
+		")"
+	if got := Pretty(n, 0); got != want {
+		t.Errorf("Pretty() = %q, want %q", got, want)
+	}
+}
+
+func TestPrettyLogicalChainBreaksLongOperandsOntoLines(t *testing.T) {
+	n := &LogicalOpNode{
+		Kind:  LogicalOpNodeKindAnd,
+		Left:  varNode("firstVeryLongConditionNameAAAAAAAAAAAAAAAAAAAA"),
+		Right: varNode("secondVeryLongConditionNameBBBBBBBBBBBBBBBBBBBB"),
+	}
+	want := "  firstVeryLongConditionNameAAAAAAAAAAAAAAAAAAAA\n" +
+		"&& secondVeryLongConditionNameBBBBBBBBBBBBBBBBBBBB"
+	if got := Pretty(n, 0); got != want {
+		t.Errorf("Pretty() = %q, want %q", got, want)
+	}
+}
+
+// TestPrettyParenthesizesMultilineOperand reproduces a 3-way || chain nested
+// as the left, lower-precedence operand of a long &&: the || chain is long
+// enough to need its own line breaks, and the whole thing needs parens to
+// round-trip. The parens must go on their own lines rather than being
+// concatenated onto the child's already-indented text.
+func TestPrettyParenthesizesMultilineOperand(t *testing.T) {
+	or := &LogicalOpNode{
+		Kind: LogicalOpNodeKindOr,
+		Left: &LogicalOpNode{
+			Kind:  LogicalOpNodeKindOr,
+			Left:  varNode("orLeftVeryLongNameAAAAAAAAAAAA"),
+			Right: varNode("orRightVeryLongNameBBBBBBBBBBBB"),
+		},
+		Right: varNode("orThirdVeryLongNameCC"),
+	}
+	n := &LogicalOpNode{
+		Kind:  LogicalOpNodeKindAnd,
+		Left:  or,
+		Right: varNode("finalVeryLongConditionDDDDDDDDDD"),
+	}
+	want := "  (\n" +
+		"    orLeftVeryLongNameAAAAAAAAAAAA\n" +
+		"  || orRightVeryLongNameBBBBBBBBBBBB\n" +
+		"  || orThirdVeryLongNameCC\n" +
+		"  )\n" +
+		"&& finalVeryLongConditionDDDDDDDDDD"
+	if got := Pretty(n, 0); got != want {
+		t.Errorf("Pretty() =\n%s\nwant:\n%s", got, want)
+	}
+}
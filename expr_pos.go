@@ -0,0 +1,96 @@
+package actionlint
+
+// exprEndPos returns the line/column just past the last character of n's
+// End() token. This assumes that token's text is exactly len(Value) runes
+// wide and does not span a newline, which holds for every token kind the
+// expression lexer currently produces.
+func exprEndPos(n ExprNode) (line, col int) {
+	t := n.End()
+	return t.Line, t.Column + len(t.Value)
+}
+
+// exprContains reports whether the 1-based line/col position falls within
+// n's span. The position one past n's last character is not considered
+// part of n: it belongs to whatever starts there instead (e.g. for "a[0]",
+// the column right after "a" is the "[" of the enclosing IndexAccessNode,
+// not part of the VariableNode "a"), matching the usual convention of
+// collapsing a zero-width gap to the token that follows it.
+func exprContains(n ExprNode, line, col int) bool {
+	st := n.Token()
+	if line < st.Line || (line == st.Line && col < st.Column) {
+		return false
+	}
+	el, ec := exprEndPos(n)
+	if line > el || (line == el && col >= ec) {
+		return false
+	}
+	return true
+}
+
+// exprChildren returns the immediate children of n in the same order
+// visitExprNode visits them.
+func exprChildren(n ExprNode) []ExprNode {
+	switch n := n.(type) {
+	case *ObjectDerefNode:
+		return []ExprNode{n.Receiver}
+	case *ArrayDerefNode:
+		return []ExprNode{n.Receiver}
+	case *IndexAccessNode:
+		return []ExprNode{n.Index, n.Operand}
+	case *NotOpNode:
+		return []ExprNode{n.Operand}
+	case *CompareOpNode:
+		return []ExprNode{n.Left, n.Right}
+	case *LogicalOpNode:
+		return []ExprNode{n.Left, n.Right}
+	case *FuncCallNode:
+		return n.Args
+	default:
+		return nil
+	}
+}
+
+// PathEnclosingPos returns the tightest node of the tree rooted at root whose
+// span contains the 1-based line/col position, together with every ancestor
+// up to root. path[0] is the tightest node and the last element is root.
+//
+// exact is true when pos falls within the span of path[0] as described
+// above. When pos lies outside of root's span entirely (e.g. past the last
+// character of the expression), path is [root] and exact is false; callers
+// such as an LSP hover handler should treat that as "nothing under the
+// cursor".
+func PathEnclosingPos(root ExprNode, line, col int) (path []ExprNode, exact bool) {
+	if !exprContains(root, line, col) {
+		return []ExprNode{root}, false
+	}
+
+	path = []ExprNode{root}
+	n := root
+	for {
+		next := ExprNode(nil)
+		for _, c := range exprChildren(n) {
+			if exprContains(c, line, col) {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+		path = append(path, n)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+// Span returns n's start and end tokens, i.e. Token() and End(). It is a
+// convenience for diagnostics that need to highlight a whole subexpression,
+// e.g. "the entire `a && b || c` is always truthy", rather than just the
+// single token Token() alone would give.
+func Span(n ExprNode) (start, end *Token) {
+	return n.Token(), n.End()
+}
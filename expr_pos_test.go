@@ -0,0 +1,76 @@
+package actionlint
+
+import "testing"
+
+func tokAt(v string, line, col int) *Token {
+	return &Token{Value: v, Line: line, Column: col}
+}
+
+func TestPathEnclosingPosFindsTightestNode(t *testing.T) {
+	// github.event_name == 'push'
+	//        ^col 8 is inside "event_name"
+	left := &ObjectDerefNode{
+		Receiver: &VariableNode{Name: "github", tok: tokAt("github", 1, 1)},
+		Property: "event_name",
+		propTok:  tokAt("event_name", 1, 8),
+	}
+	setExprParent(left.Receiver, left)
+	right := &StringNode{Value: "push", tok: tokAt("'push'", 1, 22)}
+	root := &CompareOpNode{
+		Kind:  CompareOpNodeKindEq,
+		Left:  left,
+		Right: right,
+	}
+	setExprParent(left, root)
+	setExprParent(right, root)
+
+	path, exact := PathEnclosingPos(root, 1, 8)
+	if !exact {
+		t.Fatalf("exact = false, want true")
+	}
+	if _, ok := path[0].(*ObjectDerefNode); !ok {
+		t.Fatalf("path[0] = %T, want *ObjectDerefNode", path[0])
+	}
+	if path[len(path)-1] != ExprNode(root) {
+		t.Fatalf("last path element = %v, want root", path[len(path)-1])
+	}
+}
+
+func TestPathEnclosingPosOutsideSpanReturnsRootOnly(t *testing.T) {
+	root := &VariableNode{Name: "github", tok: tokAt("github", 1, 1)}
+
+	path, exact := PathEnclosingPos(root, 1, 100)
+	if exact {
+		t.Fatalf("exact = true, want false")
+	}
+	if len(path) != 1 || path[0] != ExprNode(root) {
+		t.Fatalf("path = %v, want [root]", path)
+	}
+}
+
+// TestPathEnclosingPosBoundaryBelongsToEnclosingNode covers "a[0]" (a=col1,
+// [=col2, 0=col3, ]=col4). The columns right at a leaf's boundary ('[' and
+// ']') belong to the enclosing IndexAccessNode, not to the adjacent leaf.
+func TestPathEnclosingPosBoundaryBelongsToEnclosingNode(t *testing.T) {
+	a := &VariableNode{Name: "a", tok: tokAt("a", 1, 1)}
+	zero := &IntNode{Value: 0, tok: tokAt("0", 1, 3)}
+	root := &IndexAccessNode{Operand: a, Index: zero}
+	setExprParent(a, root)
+	setExprParent(zero, root)
+
+	path, exact := PathEnclosingPos(root, 1, 2) // on '['
+	if !exact {
+		t.Fatalf("exact = false, want true")
+	}
+	if _, ok := path[0].(*IndexAccessNode); !ok {
+		t.Fatalf("path[0] = %T, want *IndexAccessNode (not the adjacent VariableNode)", path[0])
+	}
+
+	path, exact = PathEnclosingPos(root, 1, 4) // on ']'
+	if !exact {
+		t.Fatalf("exact = false, want true")
+	}
+	if _, ok := path[0].(*IndexAccessNode); !ok {
+		t.Fatalf("path[0] = %T, want *IndexAccessNode (not the adjacent IntNode)", path[0])
+	}
+}